@@ -15,7 +15,11 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
 
 	"github.com/coreos/butane/config/common"
 	fcos1_0 "github.com/coreos/butane/config/fcos/v1_0"
@@ -23,6 +27,8 @@ import (
 	fcos1_2 "github.com/coreos/butane/config/fcos/v1_2"
 	fcos1_3 "github.com/coreos/butane/config/fcos/v1_3"
 	fcos1_4_exp "github.com/coreos/butane/config/fcos/v1_4_exp"
+	fiot1_0 "github.com/coreos/butane/config/fiot/v1_0"
+	fiot1_1_exp "github.com/coreos/butane/config/fiot/v1_1_exp"
 	openshift4_8 "github.com/coreos/butane/config/openshift/v4_8"
 	openshift4_9_exp "github.com/coreos/butane/config/openshift/v4_9_exp"
 	rhcos0_1 "github.com/coreos/butane/config/rhcos/v0_1"
@@ -48,6 +54,8 @@ func init() {
 	RegisterTranslator("fcos", "1.2.0", fcos1_2.ToIgn3_2Bytes)
 	RegisterTranslator("fcos", "1.3.0", fcos1_3.ToIgn3_2Bytes)
 	RegisterTranslator("fcos", "1.4.0-experimental", fcos1_4_exp.ToIgn3_3Bytes)
+	RegisterTranslator("fiot", "1.0.0", fiot1_0.ToIgn3_4Bytes)
+	RegisterTranslator("fiot", "1.1.0-experimental", fiot1_1_exp.ToIgn3_5Bytes)
 	RegisterTranslator("openshift", "4.8.0", openshift4_8.ToConfigBytes)
 	RegisterTranslator("openshift", "4.9.0-experimental", openshift4_9_exp.ToConfigBytes)
 	RegisterTranslator("rhcos", "0.1.0", rhcos0_1.ToIgn3_2Bytes)
@@ -72,6 +80,67 @@ func getTranslator(variant string, version semver.Version) (translator, error) {
 	return t, nil
 }
 
+// splitRegistryKey reverses the "variant+version" key built by
+// RegisterTranslator.
+func splitRegistryKey(key string) (string, semver.Version) {
+	parts := strings.SplitN(key, "+", 2)
+	ver, err := semver.NewVersion(parts[1])
+	if err != nil {
+		// can't happen; we built the key ourselves in RegisterTranslator
+		panic(err)
+	}
+	return parts[0], *ver
+}
+
+// ListVariants returns the names of all variants with at least one
+// registered translator, sorted alphabetically.
+func ListVariants() []string {
+	seen := map[string]struct{}{}
+	for key := range registry {
+		variant, _ := splitRegistryKey(key)
+		seen[variant] = struct{}{}
+	}
+	variants := make([]string, 0, len(seen))
+	for variant := range seen {
+		variants = append(variants, variant)
+	}
+	sort.Strings(variants)
+	return variants
+}
+
+// ListVersions returns the versions registered for variant, oldest
+// first. A version's stability can be checked via the presence of a
+// semver prerelease component (e.g. "-experimental"), the same
+// convention RegisterTranslator's callers already use to mark a version
+// experimental.
+func ListVersions(variant string) []semver.Version {
+	var versions []semver.Version
+	for key := range registry {
+		v, ver := splitRegistryKey(key)
+		if v == variant {
+			versions = append(versions, ver)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].LessThan(versions[j])
+	})
+	return versions
+}
+
+// LatestStable returns the newest non-experimental version registered
+// for variant. ok is false if variant has no stable version registered,
+// whether because the variant doesn't exist or because it's currently
+// experimental-only.
+func LatestStable(variant string) (version semver.Version, ok bool) {
+	for _, ver := range ListVersions(variant) {
+		if len(ver.PreRelease) > 0 {
+			continue
+		}
+		version, ok = ver, true
+	}
+	return version, ok
+}
+
 // translators take a raw config and translate it to a raw Ignition config. The report returned should include any
 // errors, warnings, etc and may or may not be fatal. If report is fatal, or other errors are encountered while translating
 // translators should return an error.
@@ -103,3 +172,60 @@ func TranslateBytes(input []byte, options common.TranslateBytesOptions) ([]byte,
 
 	return translator(input, options)
 }
+
+// TranslateStream translates a stream of one or more YAML documents
+// separated by "---", writing each document's translated output to w,
+// itself separated by "---".  Each document is decoded and translated
+// independently, so documents may specify different variants and
+// versions, and the stream's documents are never all held in memory at
+// once the way a single TranslateBytes call of the concatenated input
+// would require.
+//
+// Per-document output isn't always JSON: e.g. the openshift variant
+// emits a YAML MachineConfig unless options.Raw is set.  A "---"-
+// separated stream, rather than a JSON array, accommodates that without
+// having to pick one output format for every document, and happens to
+// match the framing of the input stream this function reads.
+//
+// It returns the merged report of every document translated, even if a
+// later document fails; callers that want to stop at the first error
+// should check err as soon as it's returned.
+func TranslateStream(r io.Reader, w io.Writer, options common.TranslateBytesOptions) (report.Report, error) {
+	var overallReport report.Report
+	dec := yaml.NewDecoder(r)
+
+	for i := 0; ; i++ {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return overallReport, fmt.Errorf("error decoding YAML document %d: %v", i, err)
+		}
+
+		raw, err := yaml.Marshal(&doc)
+		if err != nil {
+			return overallReport, fmt.Errorf("error re-marshaling YAML document %d: %v", i, err)
+		}
+
+		out, docReport, err := TranslateBytes(raw, options)
+		overallReport.Merge(docReport)
+		if err != nil {
+			return overallReport, fmt.Errorf("error translating document %d: %v", i, err)
+		}
+
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return overallReport, err
+			}
+		}
+		if _, err := w.Write(out); err != nil {
+			return overallReport, err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return overallReport, err
+		}
+	}
+
+	return overallReport, nil
+}
@@ -0,0 +1,32 @@
+// Copyright 2022 Red Hat, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.)
+
+package v1_0
+
+import (
+	fcos "github.com/coreos/butane/config/fcos/v1_3"
+)
+
+// Config is the top-level schema for the fedora-iot (fiot) variant.  Fedora
+// IoT is an rpm-ostree-based Fedora derivative, already covered by FCOS's
+// own schema, so this mirrors FCOS exactly; the variant's Greenboot
+// default is applied during translation rather than by adding new fields
+// (see translate.go).
+//
+// This stable variant is pinned to a stable FCOS spec, so it doesn't
+// shift underneath users when fcos/v1_4_exp is revised; see
+// config/fiot/v1_1_exp for the experimental counterpart.
+type Config struct {
+	fcos.Config `yaml:",inline"`
+}
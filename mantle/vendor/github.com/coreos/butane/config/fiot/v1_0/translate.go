@@ -0,0 +1,118 @@
+// Copyright 2022 Red Hat, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.)
+
+package v1_0
+
+import (
+	"github.com/coreos/butane/config/common"
+	cutil "github.com/coreos/butane/config/util"
+	"github.com/coreos/butane/translate"
+
+	v3_2tov3_3 "github.com/coreos/ignition/v2/config/v3_2/translate/v3_3"
+	"github.com/coreos/ignition/v2/config/v3_3/translate/v3_4"
+	"github.com/coreos/ignition/v2/config/v3_4/types"
+	"github.com/coreos/vcontext/path"
+	"github.com/coreos/vcontext/report"
+)
+
+// greenbootHealthCheckUnit is shipped by Fedora IoT and drives
+// rollback-on-failed-upgrade.  We enable it by default unless the user
+// declares the unit themselves.
+const greenbootHealthCheckUnit = "greenboot-healthcheck.service"
+
+// ToIgn3_4Unvalidated translates the config to an Ignition config. It also
+// returns the set of translations it did so paths in the resultant config
+// can be tracked back to their source in the source config. No config
+// validation is performed on input or output.
+func (c Config) ToIgn3_4Unvalidated(options common.TranslateOptions) (types.Config, translate.TranslationSet, report.Report) {
+	cfg3_2, ts, r := c.Config.ToIgn3_2Unvalidated(options)
+	if r.IsFatal() {
+		return types.Config{}, ts, r
+	}
+
+	cfg := v3_4.Translate(v3_2tov3_3.Translate(cfg3_2))
+	EnsureGreenbootUnmasked(&cfg)
+
+	r.Merge(cutil.TranslateReportPaths(ValidateEmbeddedIgnition(cfg), ts))
+
+	return cfg, ts, r
+}
+
+// ToIgn3_4 translates the config to an Ignition config. It returns a
+// report of any errors or warnings in the source and resultant config. If
+// the report has fatal errors or it encounters other problems translating,
+// an error is returned.
+func (c Config) ToIgn3_4(options common.TranslateOptions) (types.Config, report.Report, error) {
+	cfg, r, err := cutil.Translate(c, "ToIgn3_4Unvalidated", options)
+	return cfg.(types.Config), r, err
+}
+
+// ToIgn3_4Bytes translates from a fedora-iot Butane config to a v3.4.0
+// Ignition config. It returns a report of any errors or warnings in the
+// source and resultant config. If the report has fatal errors or it
+// encounters other problems translating, an error is returned.
+func ToIgn3_4Bytes(input []byte, options common.TranslateBytesOptions) ([]byte, report.Report, error) {
+	return cutil.TranslateBytesYAML(input, &Config{}, "ToIgn3_4", options)
+}
+
+// ValidateEmbeddedIgnition parses any storage.files entry whose resolved
+// contents (from local, inline, or source) look like an Ignition config,
+// and folds the result into a report with paths rewritten to point at the
+// file that embeds it.  This mirrors the check openshift/v4_9_exp runs on
+// its MachineConfig's Storage.Files, reusing the same
+// cutil.ValidateResourceEmbeddedIgnition decode-and-parse helper; fiot
+// configs nest child Ignition configs (e.g. for a first-boot ostree
+// remote) often enough that catching a broken one at translate time, and
+// not only once it reaches the node, is worth the same treatment here.
+// It's exported so config/fiot/v1_1_exp, which tracks its own
+// experimental FCOS base rather than going through this package, can run
+// the same check on its own translated config.
+func ValidateEmbeddedIgnition(cfg types.Config) report.Report {
+	var r report.Report
+	for i, file := range cfg.Storage.Files {
+		childReport := cutil.ValidateResourceEmbeddedIgnition(file.Contents.Source, file.Contents.Compression)
+		if len(childReport.Entries) == 0 {
+			continue
+		}
+
+		filePath := path.New("json", "storage", "files", i, "contents", "source")
+		childTs := translate.NewTranslationSet("json", "json")
+		for _, entry := range childReport.Entries {
+			childTs.AddTranslation(filePath, entry.Context)
+		}
+		r.Merge(cutil.TranslateReportPaths(childReport, childTs))
+	}
+	return r
+}
+
+// EnsureGreenbootUnmasked enables the Greenboot health-check unit by
+// default, since a disabled or masked health check silently defeats the
+// point of running Fedora IoT.  If the user already declares the unit
+// themselves (to add a dropin, explicitly disable it, etc.), we leave
+// their declaration alone rather than risk emitting a duplicate Unit,
+// which Ignition's output validation rejects.  It's exported so
+// config/fiot/v1_1_exp, which tracks its own experimental FCOS base
+// rather than going through this package, can apply the same default.
+func EnsureGreenbootUnmasked(cfg *types.Config) {
+	for _, u := range cfg.Systemd.Units {
+		if u.Name == greenbootHealthCheckUnit {
+			return
+		}
+	}
+	enabled := true
+	cfg.Systemd.Units = append(cfg.Systemd.Units, types.Unit{
+		Name:    greenbootHealthCheckUnit,
+		Enabled: &enabled,
+	})
+}
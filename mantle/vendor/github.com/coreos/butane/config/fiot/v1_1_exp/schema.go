@@ -0,0 +1,30 @@
+// Copyright 2022 Red Hat, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.)
+
+package v1_1_exp
+
+import (
+	fcos "github.com/coreos/butane/config/fcos/v1_4_exp"
+)
+
+// Config is the experimental fedora-iot schema.  Unlike v1_0, which is
+// pinned to the stable fcos/v1_3 spec, this experimental tier tracks
+// FCOS's own experimental spec directly, so new FCOS experimental fields
+// are available to fiot's experimental tier immediately; they graduate
+// to a new stable fiot version once both fiot and FCOS have stabilized,
+// rather than changing the pinned v1_0, mirroring the pattern
+// FCOS uses for its own *-experimental versions.
+type Config struct {
+	fcos.Config `yaml:",inline"`
+}
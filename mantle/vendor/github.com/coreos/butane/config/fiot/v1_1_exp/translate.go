@@ -0,0 +1,63 @@
+// Copyright 2022 Red Hat, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.)
+
+package v1_1_exp
+
+import (
+	"github.com/coreos/butane/config/common"
+	"github.com/coreos/butane/config/fiot/v1_0"
+	cutil "github.com/coreos/butane/config/util"
+	"github.com/coreos/butane/translate"
+
+	"github.com/coreos/ignition/v2/config/v3_3/translate/v3_4"
+	"github.com/coreos/ignition/v2/config/v3_4/translate/v3_5"
+	"github.com/coreos/ignition/v2/config/v3_5/types"
+	"github.com/coreos/vcontext/report"
+)
+
+// ToIgn3_5Unvalidated translates the config to an Ignition config. It also
+// returns the set of translations it did so paths in the resultant config
+// can be tracked back to their source in the source config. No config
+// validation is performed on input or output.
+func (c Config) ToIgn3_5Unvalidated(options common.TranslateOptions) (types.Config, translate.TranslationSet, report.Report) {
+	cfg3_3, ts, r := c.Config.ToIgn3_3Unvalidated(options)
+	if r.IsFatal() {
+		return types.Config{}, ts, r
+	}
+
+	cfg3_4 := v3_4.Translate(cfg3_3)
+	v1_0.EnsureGreenbootUnmasked(&cfg3_4)
+
+	r.Merge(cutil.TranslateReportPaths(v1_0.ValidateEmbeddedIgnition(cfg3_4), ts))
+
+	return v3_5.Translate(cfg3_4), ts, r
+}
+
+// ToIgn3_5 translates the config to an Ignition config. It returns a
+// report of any errors or warnings in the source and resultant config. If
+// the report has fatal errors or it encounters other problems translating,
+// an error is returned.
+func (c Config) ToIgn3_5(options common.TranslateOptions) (types.Config, report.Report, error) {
+	cfg, r, err := cutil.Translate(c, "ToIgn3_5Unvalidated", options)
+	return cfg.(types.Config), r, err
+}
+
+// ToIgn3_5Bytes translates from an experimental fedora-iot Butane config
+// to a v3.5.0 Ignition config. It returns a report of any errors or
+// warnings in the source and resultant config. If the report has fatal
+// errors or it encounters other problems translating, an error is
+// returned.
+func ToIgn3_5Bytes(input []byte, options common.TranslateBytesOptions) ([]byte, report.Report, error) {
+	return cutil.TranslateBytesYAML(input, &Config{}, "ToIgn3_5", options)
+}
@@ -89,9 +89,13 @@ func (c Config) ToMachineConfig4_9Unvalidated(options common.TranslateOptions) (
 	// apply FIPS options to LUKS volumes
 	ts.Merge(addLuksFipsOptions(&mc))
 
-	// finally, check the fully desugared config for RHCOS and MCO support
+	// finally, check the fully desugared config for RHCOS and MCO support,
+	// and validate any Ignition config embedded in a file via local,
+	// inline, or source
 	r.Merge(validateRHCOSSupport(mc, ts))
-	r.Merge(validateMCOSupport(mc, ts))
+	r.Merge(c.FieldFilters().Walk(mc, ts))
+	r.Merge(validateUserSupport(mc, ts))
+	r.Merge(validateEmbeddedIgnition(mc, ts))
 
 	return mc, ts, r
 }
@@ -191,63 +195,55 @@ func validateRHCOSSupport(mc result.MachineConfig, ts translate.TranslationSet)
 	return cutil.TranslateReportPaths(r, ts)
 }
 
-// Error on fields that are rejected outright by the MCO, or that are
-// unsupported by the MCO and we want to discourage.
+// FieldFilters returns the FORBIDDEN/IMMUTABLE/BUGGED field rejections,
+// declaratively, so a new per-release restriction is a one-line addition
+// to this list instead of a new hand-rolled loop.  TRIPWIRE rejections
+// of passwd.users are handled separately by validateUserSupport.
 //
-// https://github.com/openshift/machine-config-operator/blob/d6dabadeca05/MachineConfigDaemon.md#supported-vs-unsupported-ignition-config-changes
+// FORBIDDEN - Not supported by the MCD.  If present in MC, MCD will
+// mark the node degraded.  We reject these.
 //
-// Some of these fields may have been generated by sugar (e.g. storage.trees),
-// so we work in JSON (output) space and then translate paths back to YAML
-// (input) space.  That's also the reason we do these checks after
-// translation, rather than during validation.
-func validateMCOSupport(mc result.MachineConfig, ts translate.TranslationSet) report.Report {
-	// Error classes for the purposes of this function:
-	//
-	// FORBIDDEN - Not supported by the MCD.  If present in MC, MCD will
-	// mark the node degraded.  We reject these.
-	//
-	// IMMUTABLE - Permitted in MC, passed through to Ignition, but not
-	// supported by the MCD.  MCD will mark the node degraded if the
-	// field changes after the node is provisioned.  We reject these
-	// outright to discourage their use.
-	//
-	// TRIPWIRE - A subset of fields in the containing struct are
-	// supported by the MCD.  If the struct contents change after the node
-	// is provisioned, and the struct contains unsupported fields, MCD
-	// will mark the node degraded, even if the change only affects
-	// supported fields.  We reject these.
-	//
-	// BUGGED - Ignored by the MCD but not by Ignition.  Ignition
-	// correctly applies the setting, but the MCD doesn't, and writes
-	// incorrect state to the node.
-
-	var r report.Report
-	for i := range mc.Spec.Config.Storage.Directories {
+// IMMUTABLE - Permitted in MC, passed through to Ignition, but not
+// supported by the MCD.  MCD will mark the node degraded if the
+// field changes after the node is provisioned.  We reject these
+// outright to discourage their use.
+//
+// BUGGED - Ignored by the MCD but not by Ignition.  Ignition
+// correctly applies the setting, but the MCD doesn't, and writes
+// incorrect state to the node.
+//
+// https://github.com/openshift/machine-config-operator/blob/d6dabadeca05/MachineConfigDaemon.md#supported-vs-unsupported-ignition-config-changes
+func (c Config) FieldFilters() cutil.FieldFilters {
+	return cutil.FieldFilters{}.
 		// IMMUTABLE
-		r.AddOnError(path.New("json", "spec", "config", "storage", "directories", i), common.ErrDirectorySupport)
-	}
-	for i, file := range mc.Spec.Config.Storage.Files {
-		if len(file.Append) > 0 {
-			// FORBIDDEN
-			r.AddOnError(path.New("json", "spec", "config", "storage", "files", i, "append"), common.ErrFileAppendSupport)
-		}
-		if util.NotEmpty(file.Contents.Compression) {
-			// BUGGED
-			// https://bugzilla.redhat.com/show_bug.cgi?id=1970218
-			r.AddOnError(path.New("json", "spec", "config", "storage", "files", i, "contents", "compression"), common.ErrFileCompressionSupport)
-		}
-	}
-	for i := range mc.Spec.Config.Storage.Links {
+		Append([]interface{}{"spec", "config", "storage", "directories", -1}, cutil.Deny(common.ErrDirectorySupport)).
+		// FORBIDDEN
+		Append([]interface{}{"spec", "config", "storage", "files", -1, "append"}, cutil.Deny(common.ErrFileAppendSupport)).
+		// BUGGED
+		// https://bugzilla.redhat.com/show_bug.cgi?id=1970218
+		Append([]interface{}{"spec", "config", "storage", "files", -1, "contents", "compression"}, cutil.Deny(common.ErrFileCompressionSupport)).
 		// IMMUTABLE
-		// If you change this to be less restrictive without adding
-		// link support in the MCO, consider what should happen if
-		// the user specifies a storage.tree that includes symlinks.
-		r.AddOnError(path.New("json", "spec", "config", "storage", "links", i), common.ErrLinkSupport)
-	}
-	for i := range mc.Spec.Config.Passwd.Groups {
+		// If you change this to be less restrictive without adding link
+		// support in the MCO, consider what should happen if the user
+		// specifies a storage.tree that includes symlinks.
+		Append([]interface{}{"spec", "config", "storage", "links", -1}, cutil.Deny(common.ErrLinkSupport)).
 		// IMMUTABLE
-		r.AddOnError(path.New("json", "spec", "config", "passwd", "groups", i), common.ErrGroupSupport)
-	}
+		Append([]interface{}{"spec", "config", "passwd", "groups", -1}, cutil.Deny(common.ErrGroupSupport))
+}
+
+// Error on fields that are rejected outright by the MCO, or that are
+// unsupported by the MCO and we want to discourage.  Unlike the
+// FieldFilters above, which field of passwd.users is at issue depends on
+// whether the user is "core", so this can't be expressed as a plain path
+// glob.
+//
+// TRIPWIRE - A subset of fields in the containing struct are
+// supported by the MCD.  If the struct contents change after the node
+// is provisioned, and the struct contains unsupported fields, MCD
+// will mark the node degraded, even if the change only affects
+// supported fields.  We reject these.
+func validateUserSupport(mc result.MachineConfig, ts translate.TranslationSet) report.Report {
+	var r report.Report
 	for i, user := range mc.Spec.Config.Passwd.Users {
 		if user.Name == "core" {
 			// SSHAuthorizedKeys is managed; other fields are not
@@ -262,15 +258,43 @@ func validateMCOSupport(mc result.MachineConfig, ts translate.TranslationSet) re
 				default:
 					if fv.IsValid() && !fv.IsZero() {
 						tag := strings.Split(ft.Tag.Get("json"), ",")[0]
-						// TRIPWIRE
 						r.AddOnError(path.New("json", "spec", "config", "passwd", "users", i, tag), common.ErrUserFieldSupport)
 					}
 				}
 			}
 		} else {
-			// TRIPWIRE
 			r.AddOnError(path.New("json", "spec", "config", "passwd", "users", i), common.ErrUserNameSupport)
 		}
 	}
 	return cutil.TranslateReportPaths(r, ts)
 }
+
+// Parse any file whose resolved contents (from local, inline, or source)
+// look like an Ignition config, and fold the result into our own report
+// with paths rewritten to point at the file that embeds it.  Today
+// Butane happily embeds a broken child Ignition config and lets it fail
+// later on the node; this catches it at translate time instead, which
+// matters because MachineConfigs are a common place to nest a full
+// Ignition config inside a single storage.files entry.
+//
+// The decode-and-parse logic itself lives in
+// cutil.ValidateResourceEmbeddedIgnition so other variants can reuse it
+// on their own Storage.Files; this is just the openshift-specific loop
+// and path bookkeeping.
+func validateEmbeddedIgnition(mc result.MachineConfig, ts translate.TranslationSet) report.Report {
+	var r report.Report
+	for i, file := range mc.Spec.Config.Storage.Files {
+		childReport := cutil.ValidateResourceEmbeddedIgnition(file.Contents.Source, file.Contents.Compression)
+		if len(childReport.Entries) == 0 {
+			continue
+		}
+
+		filePath := path.New("json", "spec", "config", "storage", "files", i, "contents", "source")
+		childTs := translate.NewTranslationSet("json", "json")
+		for _, entry := range childReport.Entries {
+			childTs.AddTranslation(filePath, entry.Context)
+		}
+		r.Merge(cutil.TranslateReportPaths(childReport, childTs))
+	}
+	return cutil.TranslateReportPaths(r, ts)
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 Red Hat, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.)
+
+package util
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+
+	"github.com/coreos/ignition/v2/config"
+	"github.com/coreos/vcontext/report"
+	"github.com/vincent-petithory/dataurl"
+)
+
+// LooksLikeIgnition does a cheap check for "is this JSON with a non-empty
+// ignition.version field" before paying for a full Ignition parse.  Most
+// storage.files entries are plain data and were never meant to be parsed
+// as Ignition, so we only go further down that path for content that's
+// actually shaped like it.
+func LooksLikeIgnition(contents []byte) bool {
+	var probe struct {
+		Ignition struct {
+			Version string `json:"version"`
+		} `json:"ignition"`
+	}
+	if err := json.Unmarshal(contents, &probe); err != nil {
+		return false
+	}
+	return probe.Ignition.Version != ""
+}
+
+// ValidateEmbeddedIgnition parses file contents that look like an
+// Ignition config, so that a child config embedded via local, inline, or
+// source resolves its own errors and warnings at translate time instead
+// of only failing once it reaches the node.  It's a no-op (empty report,
+// no error) for content that doesn't look like Ignition JSON at all,
+// since callers use this speculatively on arbitrary file contents.
+//
+// Parsing is dispatched by the embedded config's own declared
+// ignition.version via the top-level config package, rather than a
+// single fixed spec version, since a perfectly valid 3.4 or 3.5 child
+// shouldn't be rejected just because the variant embedding it happens to
+// target an older Ignition spec itself.
+//
+// The returned report's paths are rooted at "json" as produced by the
+// embedded config's own parse; callers are expected to rewrite them to
+// point at the containing file's path (e.g. storage.files[i].contents)
+// via translate.TranslationSet before merging into their own report.
+func ValidateEmbeddedIgnition(contents []byte) report.Report {
+	if !LooksLikeIgnition(contents) {
+		return report.Report{}
+	}
+
+	// Parse() also rejects the config if it isn't valid Ignition, even
+	// when it happens to parse as JSON with an ignition.version field;
+	// that's intentional, since such a file was almost certainly meant
+	// to be an Ignition config.
+	_, r, _ := config.Parse(contents)
+	return r
+}
+
+// ValidateResourceEmbeddedIgnition decodes source and compression, the
+// resolved local/inline/source contents of a storage.files entry (always
+// a *string regardless of Ignition spec version) and its compression
+// field, and validates the decompressed contents via
+// ValidateEmbeddedIgnition.  It's a no-op if source is empty or isn't a
+// data URL we can decode (e.g. a remote http(s) source, which we don't
+// fetch here).  Factoring the decode out of the per-variant loop means
+// any variant's translate.go can check its own translated Storage.Files
+// with a one-line call, instead of duplicating the decode-and-parse
+// dance that used to live only in the openshift translator.
+//
+// compression must be checked here rather than left to
+// ValidateEmbeddedIgnition: Butane auto-gzips inline/local file contents
+// by default (unless the variant sets NoResourceAutoCompression), so by
+// the time a resource reaches this function its data URL usually holds
+// gzip bytes, not the plaintext Ignition JSON a caller might expect.
+func ValidateResourceEmbeddedIgnition(source, compression *string) report.Report {
+	if source == nil || *source == "" {
+		return report.Report{}
+	}
+	decoded, err := dataurl.DecodeString(*source)
+	if err != nil {
+		return report.Report{}
+	}
+
+	contents := decoded.Data
+	if compression != nil && *compression == "gzip" {
+		gz, err := gzip.NewReader(bytes.NewReader(contents))
+		if err != nil {
+			return report.Report{}
+		}
+		defer gz.Close()
+		contents, err = io.ReadAll(gz)
+		if err != nil {
+			return report.Report{}
+		}
+	}
+
+	return ValidateEmbeddedIgnition(contents)
+}
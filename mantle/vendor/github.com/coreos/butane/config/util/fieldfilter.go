@@ -0,0 +1,146 @@
+// Copyright 2022 Red Hat, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.)
+
+package util
+
+import (
+	"encoding/json"
+
+	"github.com/coreos/butane/translate"
+
+	"github.com/coreos/vcontext/path"
+	"github.com/coreos/vcontext/report"
+)
+
+// FieldFilter describes what happens when a FieldFilters walk matches a
+// field.
+type FieldFilter struct {
+	// Err is the error or warning reported for each match.
+	Err error
+	// Warning demotes the match from an error to a warning.  Used for
+	// fields we want to discourage without outright rejecting.
+	Warning bool
+}
+
+// Deny is a convenience constructor for the common case of an
+// unconditional error.
+func Deny(err error) FieldFilter {
+	return FieldFilter{Err: err}
+}
+
+// Discourage is a convenience constructor for a non-fatal warning.
+func Discourage(err error) FieldFilter {
+	return FieldFilter{Err: err, Warning: true}
+}
+
+// fieldFilterEntry pairs a JSON-space path glob with the filter to apply
+// to every match.  "*" in Glob matches any single path component,
+// whether it's a map key or a slice index; Glob itself is relative to
+// the root of the config passed to Walk.
+type fieldFilterEntry struct {
+	Glob   []interface{}
+	Filter FieldFilter
+}
+
+// FieldFilters is an ordered list of path globs to apply to a translated
+// config.  Each variant builds its own list, typically by appending to
+// its parent variant's, so a new per-release restriction (e.g. rejecting
+// a field that's only unsupported starting in one release) is a one-line
+// addition rather than a new hand-rolled validation function.
+type FieldFilters []fieldFilterEntry
+
+// Append returns a copy of fs with an additional filter appended,
+// letting a variant extend its parent's filters without mutating them.
+// glob components must be strings (matching a JSON object key, or "*"
+// for any key) or ints (matching a JSON array index, or -1 for any
+// index).
+func (fs FieldFilters) Append(glob []interface{}, filter FieldFilter) FieldFilters {
+	out := make(FieldFilters, len(fs), len(fs)+1)
+	copy(out, fs)
+	return append(out, fieldFilterEntry{glob, filter})
+}
+
+// Walk matches every filter in fs against cfg (typically the fully
+// desugared, translated config) and returns a report of each match,
+// translated from JSON space back into YAML space via ts.
+func (fs FieldFilters) Walk(cfg interface{}, ts translate.TranslationSet) report.Report {
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		// cfg is always a plain JSON-shaped struct produced by our own
+		// translators
+		panic(err)
+	}
+	var tree interface{}
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		panic(err)
+	}
+
+	var r report.Report
+	for _, entry := range fs {
+		walkMatches(tree, path.New("json"), entry.Glob, func(p path.ContextPath) {
+			if entry.Filter.Warning {
+				r.AddOnWarn(p, entry.Filter.Err)
+			} else {
+				r.AddOnError(p, entry.Filter.Err)
+			}
+		})
+	}
+	return TranslateReportPaths(r, ts)
+}
+
+// walkMatches recursively descends node in lockstep with the remaining
+// components of glob, calling match with the concrete path (built up in
+// cur) for every node that glob fully matches.  A "*" component in glob
+// matches any single map key or slice index.
+func walkMatches(node interface{}, cur path.ContextPath, glob []interface{}, match func(path.ContextPath)) {
+	if len(glob) == 0 {
+		match(cur)
+		return
+	}
+
+	component := glob[0]
+	rest := glob[1:]
+
+	switch v := node.(type) {
+	case map[string]interface{}:
+		key, ok := component.(string)
+		if !ok {
+			// int components only match array indices
+			return
+		}
+		if key != "*" {
+			if child, ok := v[key]; ok {
+				walkMatches(child, cur.Append(key), rest, match)
+			}
+			return
+		}
+		for key, child := range v {
+			walkMatches(child, cur.Append(key), rest, match)
+		}
+	case []interface{}:
+		if idx, ok := component.(int); ok && idx != -1 {
+			if idx >= 0 && idx < len(v) {
+				walkMatches(v[idx], cur.Append(idx), rest, match)
+			}
+			return
+		}
+		if component != -1 && component != "*" {
+			// string components other than "*" only match object keys
+			return
+		}
+		for i, child := range v {
+			walkMatches(child, cur.Append(i), rest, match)
+		}
+	}
+}